@@ -1,10 +1,73 @@
 package httpware
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 )
 
+// AuthOutcome describes the result of an authentication decision reported
+// through AuthEvent.
+type AuthOutcome string
+
+const (
+	// AuthOutcomeOK indicates the request presented valid credentials and
+	// was allowed to proceed.
+	AuthOutcomeOK AuthOutcome = "ok"
+
+	// AuthOutcomeUnauthorized indicates the request did not present usable
+	// credentials, e.g. a missing or malformed Authorization header.
+	AuthOutcomeUnauthorized AuthOutcome = "unauthorized"
+
+	// AuthOutcomeForbidden indicates the request presented credentials
+	// that AuthFunc rejected.
+	AuthOutcomeForbidden AuthOutcome = "forbidden"
+)
+
+// AuthEvent describes a single allow/deny decision made by BasicAuth,
+// suitable for structured audit logging or feeding a SIEM / fail2ban-style
+// pipeline.
+type AuthEvent struct {
+	// Outcome is the result of the authentication decision.
+	Outcome AuthOutcome
+
+	// Username is the username presented in the request, if any. The
+	// password is never included.
+	Username string
+
+	// RemoteAddr is the request's RemoteAddr, typically "IP:port".
+	RemoteAddr string
+
+	// Method is the request's HTTP method.
+	Method string
+
+	// Path is the request's URL path.
+	Path string
+
+	// Reason is a short, human-readable explanation of the outcome, e.g.
+	// "missing or malformed credentials" or "auth func rejected credentials".
+	Reason string
+}
+
+// defaultAuthEvent logs ev via the default slog.Logger at a level based on
+// its Outcome.
+func defaultAuthEvent(ev AuthEvent) {
+	attrs := []any{
+		slog.String("outcome", string(ev.Outcome)),
+		slog.String("username", ev.Username),
+		slog.String("remote_addr", ev.RemoteAddr),
+		slog.String("method", ev.Method),
+		slog.String("path", ev.Path),
+		slog.String("reason", ev.Reason),
+	}
+	if ev.Outcome == AuthOutcomeOK {
+		slog.Info("basic auth", attrs...)
+	} else {
+		slog.Warn("basic auth", attrs...)
+	}
+}
+
 // BasicAuthOptions represents the configurable settings of BasicAuth.
 type BasicAuthOptions struct {
 	// The name of the protected scope. Defaults to "Restricted".
@@ -26,6 +89,19 @@ type BasicAuthOptions struct {
 	// An http.Handler for forbidden (403) requests. The default handler calls
 	// http.Error with status code 403 and writes http.StatusText to the body.
 	Forbidden http.Handler
+
+	// OnAuthEvent, if set, is called with an AuthEvent for every allow/deny
+	// decision made by BasicAuth. It defaults to a function that logs the
+	// event via log/slog, letting operators wire basic auth into a SIEM or
+	// fail2ban-style pipeline without wrapping the handler.
+	OnAuthEvent func(ev AuthEvent)
+
+	// StripCredentials removes the Authorization header and clears
+	// r.URL.User after a successful authentication, before calling the
+	// next handler, so downstream reverse-proxied services and access logs
+	// never see the caller's credentials. The authenticated username is
+	// still available to handlers via UserFromContext.
+	StripCredentials bool
 }
 
 // BasicAuth enforces the HTTP Basic Authentication Scheme for an http.Handler.
@@ -61,14 +137,39 @@ func (a basicAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		username, password, ok = r.BasicAuth()
 	}
 	if !ok || username == "" || (!a.opts.AllowEmptyPassword && password == "") {
+		a.reportEvent(r, AuthOutcomeUnauthorized, username, "missing or malformed credentials")
 		a.unauthorized(w, r)
 		return
 	}
 	if !a.opts.AuthFunc(username, password, r) {
+		a.reportEvent(r, AuthOutcomeForbidden, username, "auth func rejected credentials")
 		a.forbidden(w, r)
 		return
 	}
-	a.h.ServeHTTP(w, r)
+	a.reportEvent(r, AuthOutcomeOK, username, "")
+	ctx := context.WithValue(r.Context(), userContextKey, username)
+	if a.opts.StripCredentials {
+		r.Header.Del("Authorization")
+		r.URL.User = nil
+	}
+	a.h.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// reportEvent invokes opts.OnAuthEvent, or defaultAuthEvent if unset, with
+// an AuthEvent describing the decision.
+func (a basicAuth) reportEvent(r *http.Request, outcome AuthOutcome, username, reason string) {
+	onAuthEvent := a.opts.OnAuthEvent
+	if onAuthEvent == nil {
+		onAuthEvent = defaultAuthEvent
+	}
+	onAuthEvent(AuthEvent{
+		Outcome:    outcome,
+		Username:   username,
+		RemoteAddr: r.RemoteAddr,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Reason:     reason,
+	})
 }
 
 func (a basicAuth) unauthorized(w http.ResponseWriter, r *http.Request) {
@@ -91,22 +192,6 @@ func (a basicAuth) forbidden(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 }
 
-func bearerAuth() func(http.Handler) http.Handler {
-	return func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			panic("implement me")
-		})
-	}
-}
-
-func digestAuth() func(http.Handler) http.Handler {
-	return func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			panic("implement me")
-		})
-	}
-}
-
 func hobaAuth() func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -122,11 +207,3 @@ func mutualAuth() func(http.Handler) http.Handler {
 		})
 	}
 }
-
-func aWS4Auth() func(http.Handler) http.Handler {
-	return func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			panic("implement me")
-		})
-	}
-}
@@ -169,6 +169,101 @@ func TestBasicAuth(t *testing.T) {
 	}
 }
 
+func TestBasicAuth_OnAuthEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		authFunc    func(string, string, *http.Request) bool
+		req         *http.Request
+		wantOutcome AuthOutcome
+		wantUser    string
+	}{
+		{
+			name:        "Unauthorized",
+			authFunc:    func(string, string, *http.Request) bool { return true },
+			req:         basicAuthRequest("", "", false),
+			wantOutcome: AuthOutcomeUnauthorized,
+			wantUser:    "",
+		},
+		{
+			name:        "Forbidden",
+			authFunc:    func(string, string, *http.Request) bool { return false },
+			req:         basicAuthRequest("user", "pass", false),
+			wantOutcome: AuthOutcomeForbidden,
+			wantUser:    "user",
+		},
+		{
+			name:        "OK",
+			authFunc:    func(string, string, *http.Request) bool { return true },
+			req:         basicAuthRequest("user", "pass", false),
+			wantOutcome: AuthOutcomeOK,
+			wantUser:    "user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AuthEvent
+			opts := BasicAuthOptions{
+				AuthFunc:    tt.authFunc,
+				OnAuthEvent: func(ev AuthEvent) { got = ev },
+			}
+			rec := httptest.NewRecorder()
+			BasicAuth(opts)(handleOK()).ServeHTTP(rec, tt.req)
+			if got.Outcome != tt.wantOutcome {
+				t.Errorf("Outcome: got %q, want %q", got.Outcome, tt.wantOutcome)
+			}
+			if got.Username != tt.wantUser {
+				t.Errorf("Username: got %q, want %q", got.Username, tt.wantUser)
+			}
+			if got.Reason == "" && tt.wantOutcome != AuthOutcomeOK {
+				t.Errorf("Reason: got empty, want non-empty for outcome %q", tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestBasicAuth_StripCredentials(t *testing.T) {
+	var gotAuthHeader string
+	var gotUser string
+	var gotOK bool
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		gotUser, gotOK = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	opts := BasicAuthOptions{
+		AuthFunc:         func(string, string, *http.Request) bool { return true },
+		StripCredentials: true,
+	}
+	req := basicAuthRequest("user", "pass", false)
+	rec := httptest.NewRecorder()
+	BasicAuth(opts)(h).ServeHTTP(rec, req)
+
+	if gotAuthHeader != "" {
+		t.Errorf("Authorization header: got %q, want empty", gotAuthHeader)
+	}
+	if !gotOK || gotUser != "user" {
+		t.Errorf("UserFromContext: got (%q, %v), want (%q, true)", gotUser, gotOK, "user")
+	}
+}
+
+func TestBasicAuth_StripCredentialsDisabled(t *testing.T) {
+	var gotAuthHeader string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	})
+	opts := BasicAuthOptions{
+		AuthFunc: func(string, string, *http.Request) bool { return true },
+	}
+	req := basicAuthRequest("user", "pass", false)
+	rec := httptest.NewRecorder()
+	BasicAuth(opts)(h).ServeHTTP(rec, req)
+
+	if gotAuthHeader == "" {
+		t.Error("Authorization header: got empty, want preserved when StripCredentials is false")
+	}
+}
+
 func basicAuthRequest(username, password string, inURL bool) *http.Request {
 	r := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
 	if inURL {
@@ -0,0 +1,129 @@
+package httpware
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCanonicalRequestKnownVector checks canonicalRequest and the derived
+// signature against independently computed values for a fixed request, the
+// "get-vanilla-query-order-key-case" case from the AWS Signature Version 4
+// test suite.
+func TestCanonicalRequestKnownVector(t *testing.T) {
+	const (
+		secretKey = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+		region    = "us-east-1"
+		service   = "service"
+		amzDate   = "20150830T123600Z"
+		dateStamp = "20150830"
+	)
+	const wantCanonicalRequest = "GET\n" +
+		"/\n" +
+		"Param1=value1&Param2=value2\n" +
+		"host:example.amazonaws.com\n" +
+		"x-amz-date:20150830T123600Z\n" +
+		"\n" +
+		"host;x-amz-date\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	const wantSignature = "8d42a939124c7caa12286d7c29afe0cd5356d0897447891c374aba0aceb3b785"
+
+	r := httptest.NewRequest(http.MethodGet, "https://example.amazonaws.com/?Param2=value2&Param1=value1", nil)
+	r.Host = "example.amazonaws.com"
+	r.Header.Set("X-Amz-Date", amzDate)
+
+	sig := &sigV4{
+		dateStamp:     dateStamp,
+		region:        region,
+		service:       service,
+		signedHeaders: []string{"host", "x-amz-date"},
+		payloadHash:   hexSHA256(""),
+	}
+
+	got := canonicalRequest(r, sig)
+	if got != wantCanonicalRequest {
+		t.Fatalf("canonicalRequest() =\n%q\nwant:\n%q", got, wantCanonicalRequest)
+	}
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + sig.scope() + "\n" + hexSHA256(got)
+	signingKey := deriveSigningKey(secretKey, dateStamp, region, service)
+	gotSignature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	if gotSignature != wantSignature {
+		t.Fatalf("signature = %s, want %s", gotSignature, wantSignature)
+	}
+}
+
+func TestAWS4AuthEndToEnd(t *testing.T) {
+	const (
+		accessKey = "AKIDEXAMPLE"
+		secretKey = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+		region    = "us-east-1"
+		service   = "service"
+		amzDate   = "20150830T123600Z"
+		dateStamp = "20150830"
+	)
+
+	opts := AWS4Options{
+		Region:       region,
+		Service:      service,
+		MaxClockSkew: 100 * 365 * 24 * time.Hour,
+		SecretLookup: func(ak string) (string, bool) {
+			if ak != accessKey {
+				return "", false
+			}
+			return secretKey, true
+		},
+	}
+	h := AWS4Auth(opts)(handleOK())
+
+	r := httptest.NewRequest(http.MethodGet, "https://example.amazonaws.com/?Param2=value2&Param1=value1", nil)
+	r.Host = "example.amazonaws.com"
+	r.Header.Set("X-Amz-Date", amzDate)
+	r.Header.Set("X-Amz-Content-Sha256", hexSHA256(""))
+
+	sig := &sigV4{
+		dateStamp:     dateStamp,
+		region:        region,
+		service:       service,
+		signedHeaders: []string{"host", "x-amz-date"},
+		payloadHash:   hexSHA256(""),
+	}
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + sig.scope() + "\n" + hexSHA256(canonicalRequest(r, sig))
+	signature := hex.EncodeToString(hmacSHA256(deriveSigningKey(secretKey, dateStamp, region, service), stringToSign))
+
+	r.Header.Set("Authorization",
+		"AWS4-HMAC-SHA256 Credential="+accessKey+"/"+dateStamp+"/"+region+"/"+service+"/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, Signature="+signature)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestAWS4AuthWrongSignature(t *testing.T) {
+	opts := AWS4Options{
+		MaxClockSkew: 100 * 365 * 24 * time.Hour,
+		SecretLookup: func(ak string) (string, bool) { return "secret", true },
+	}
+	h := AWS4Auth(opts)(handleOK())
+
+	r := httptest.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	r.Host = "example.amazonaws.com"
+	r.Header.Set("X-Amz-Date", "20150830T123600Z")
+	r.Header.Set("X-Amz-Content-Sha256", hexSHA256(""))
+	r.Header.Set("Authorization",
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, Signature=0000000000000000000000000000000000000000000000000000000000000")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,119 @@
+package httpware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TokenValidator validates a bearer token and returns the claims it carries.
+// It should return a non-nil error for any expired, malformed, or otherwise
+// invalid token.
+type TokenValidator func(ctx context.Context, token string) (Claims, error)
+
+// BearerAuthOptions represents the configurable settings of BearerAuth.
+type BearerAuthOptions struct {
+	// The name of the protected scope. Defaults to "Restricted".
+	Realm string
+
+	// A function used to validate the bearer token presented in a request.
+	TokenValidator TokenValidator
+
+	// An http.Handler for unauthorized (401) requests. The default handler
+	// sets WWW-Authenticate per RFC 6750 and calls http.Error with status
+	// code 401.
+	Unauthorized http.Handler
+
+	// An http.Handler for forbidden (403) requests. The default handler
+	// calls http.Error with status code 403 and writes http.StatusText to
+	// the body. BearerAuth itself never responds with 403, and RequireScope
+	// always uses the default handler, so this handler is not called.
+	Forbidden http.Handler
+}
+
+// BearerAuth enforces the HTTP Bearer Authentication Scheme for an
+// http.Handler.
+//
+// The token is parsed from the Authorization header as defined by RFC 6750
+// and validated with opts.TokenValidator. On success, the resulting Claims
+// are injected into the request context and can be retrieved with
+// ClaimsFromContext.
+//
+// See https://tools.ietf.org/html/rfc6750 for details about the auth scheme.
+func BearerAuth(opts BearerAuthOptions) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return bearerAuth{h: h, opts: opts}
+	}
+}
+
+type bearerAuth struct {
+	h    http.Handler
+	opts BearerAuthOptions
+}
+
+func (a bearerAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok {
+		a.unauthorized(w, r, "")
+		return
+	}
+	claims, err := a.opts.TokenValidator(r.Context(), token)
+	if err != nil {
+		a.unauthorized(w, r, "invalid_token")
+		return
+	}
+	ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+	a.h.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// bearerToken extracts the token from the Authorization header, e.g.
+// "Bearer <token>".
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+func (a bearerAuth) unauthorized(w http.ResponseWriter, r *http.Request, reason string) {
+	if a.opts.Unauthorized != nil {
+		a.opts.Unauthorized.ServeHTTP(w, r)
+		return
+	}
+	if a.opts.Realm == "" {
+		a.opts.Realm = "Restricted"
+	}
+	if reason == "" {
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q`, a.opts.Realm))
+	} else {
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q, error=%q`, a.opts.Realm, reason))
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+func (a bearerAuth) forbidden(w http.ResponseWriter, r *http.Request) {
+	if a.opts.Forbidden != nil {
+		a.opts.Forbidden.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+}
+
+// RequireScope returns a middleware that, composed on top of BearerAuth,
+// rejects requests whose Claims (as injected by BearerAuth) do not include
+// scope in their space-delimited "scope" claim.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok || !claims.HasScope(scope) {
+				bearerAuth{}.forbidden(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package httpware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerAuth(t *testing.T) {
+	validator := func(_ context.Context, token string) (Claims, error) {
+		if token != "good-token" {
+			return nil, errInvalidToken
+		}
+		return Claims{"sub": "alice", "scope": "read write"}, nil
+	}
+
+	tests := []struct {
+		name       string
+		authHeader string
+		wantStatus int
+		wantAuthN  string
+	}{
+		{
+			name:       "Missing",
+			authHeader: "",
+			wantStatus: http.StatusUnauthorized,
+			wantAuthN:  `Bearer realm="Restricted"`,
+		},
+		{
+			name:       "WrongScheme",
+			authHeader: "Basic Zm9vOmJhcg==",
+			wantStatus: http.StatusUnauthorized,
+			wantAuthN:  `Bearer realm="Restricted"`,
+		},
+		{
+			name:       "InvalidToken",
+			authHeader: "Bearer bad-token",
+			wantStatus: http.StatusUnauthorized,
+			wantAuthN:  `Bearer realm="Restricted", error="invalid_token"`,
+		},
+		{
+			name:       "Valid",
+			authHeader: "Bearer good-token",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+			if tt.authHeader != "" {
+				r.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			BearerAuth(BearerAuthOptions{TokenValidator: validator})(handleOK()).ServeHTTP(rec, r)
+
+			if got := rec.Code; got != tt.wantStatus {
+				t.Fatalf("status code: got %d, want %d", got, tt.wantStatus)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantAuthN {
+				t.Errorf("WWW-Authenticate: got %q, want %q", got, tt.wantAuthN)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	validator := func(_ context.Context, token string) (Claims, error) {
+		return Claims{"scope": "read"}, nil
+	}
+	auth := BearerAuth(BearerAuthOptions{TokenValidator: validator})
+	h := auth(RequireScope("write")(handleOK()))
+
+	r := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+	r.Header.Set("Authorization", "Bearer any-token")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, r)
+
+	if got, want := rec.Code, http.StatusForbidden; got != want {
+		t.Fatalf("status code: got %d, want %d", got, want)
+	}
+}
+
+var errInvalidToken = &tokenError{"invalid token"}
+
+type tokenError struct{ msg string }
+
+func (e *tokenError) Error() string { return e.msg }
@@ -0,0 +1,60 @@
+package httpware
+
+import (
+	"context"
+	"strings"
+)
+
+// contextKey is an unexported type for context keys defined in this package,
+// so values set here never collide with keys from other packages.
+type contextKey int
+
+const (
+	claimsContextKey contextKey = iota
+	userContextKey
+)
+
+// Claims holds the claims extracted from a verified bearer token, such as
+// the decoded JWT payload or an introspection response.
+type Claims map[string]interface{}
+
+// Subject returns the "sub" claim, if present.
+func (c Claims) Subject() string {
+	return c.stringClaim("sub")
+}
+
+// Scope returns the "scope" claim, a space-delimited list of scopes as
+// defined by RFC 6749, if present.
+func (c Claims) Scope() string {
+	return c.stringClaim("scope")
+}
+
+// HasScope reports whether scope appears in the space-delimited "scope"
+// claim.
+func (c Claims) HasScope(scope string) bool {
+	for _, s := range strings.Fields(c.Scope()) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Claims) stringClaim(name string) string {
+	v, _ := c[name].(string)
+	return v
+}
+
+// ClaimsFromContext returns the Claims injected into ctx by BearerAuth, if
+// any.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(Claims)
+	return claims, ok
+}
+
+// UserFromContext returns the username injected into ctx by BasicAuth after
+// a successful authentication, if any.
+func UserFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(userContextKey).(string)
+	return user, ok
+}
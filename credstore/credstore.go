@@ -0,0 +1,126 @@
+// Package credstore provides a bcrypt-backed credential store for use with
+// httpware.BasicAuthOptions.AuthFunc, including brute-force rate limiting.
+package credstore
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// expireCheckInterval is how often stale rate-limit entries are swept.
+const expireCheckInterval = time.Minute
+
+// dummyHash is compared against on every failed username lookup so that
+// verifying an unknown user takes roughly as long as verifying a known one,
+// denying an attacker a timing oracle for username enumeration.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("credstore-dummy-password"), bcrypt.DefaultCost)
+
+// CredentialStore maps usernames to bcrypt password hashes and throttles
+// authentication attempts per source IP.
+type CredentialStore struct {
+	mu    sync.RWMutex
+	users map[string]string
+
+	limiter *rateLimiter
+	done    chan struct{}
+}
+
+// New returns a CredentialStore that allows at most one authentication
+// attempt per minInterval from any given source IP.
+func New(minInterval time.Duration) *CredentialStore {
+	s := &CredentialStore{
+		users:   make(map[string]string),
+		limiter: newRateLimiter(minInterval),
+		done:    make(chan struct{}),
+	}
+	go s.expireLoop()
+	return s
+}
+
+// NewPasswordHash hashes plaintext with bcrypt for storage via Set.
+func NewPasswordHash(plaintext string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Set stores passwordHash, as produced by NewPasswordHash, for username.
+func (s *CredentialStore) Set(username, passwordHash string) {
+	s.mu.Lock()
+	s.users[username] = passwordHash
+	s.mu.Unlock()
+}
+
+// Delete removes username from the store.
+func (s *CredentialStore) Delete(username string) {
+	s.mu.Lock()
+	delete(s.users, username)
+	s.mu.Unlock()
+}
+
+// Close stops the store's background rate-limit expiry goroutine. It is
+// safe to call Close more than once.
+func (s *CredentialStore) Close() error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	return nil
+}
+
+// AuthFunc adapts s into a function usable as httpware.BasicAuthOptions.AuthFunc.
+func (s *CredentialStore) AuthFunc() func(username, password string, r *http.Request) bool {
+	return func(username, password string, r *http.Request) bool {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		if !s.limiter.Allow(ip) {
+			return false
+		}
+		return s.verify(username, password)
+	}
+}
+
+// verify reports whether password is correct for username, comparing
+// usernames in constant time and always performing a bcrypt comparison
+// (against dummyHash when username is unknown) so that response time does
+// not reveal whether the username exists.
+func (s *CredentialStore) verify(username, password string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hash := dummyHash
+	found := false
+	for u, h := range s.users {
+		if subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 {
+			found = true
+			hash = []byte(h)
+		}
+	}
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	return found && err == nil
+}
+
+// expireLoop periodically clears rate-limit entries that have aged out,
+// until Close is called.
+func (s *CredentialStore) expireLoop() {
+	ticker := time.NewTicker(expireCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.limiter.Expire(s.limiter.interval)
+		}
+	}
+}
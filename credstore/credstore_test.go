@@ -0,0 +1,73 @@
+package credstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCredentialStoreAuthFunc(t *testing.T) {
+	hash, err := NewPasswordHash("pass")
+	if err != nil {
+		t.Fatalf("NewPasswordHash() error = %v", err)
+	}
+
+	store := New(time.Hour)
+	defer store.Close()
+	store.Set("user", hash)
+
+	authFunc := store.AuthFunc()
+
+	tests := []struct {
+		name       string
+		username   string
+		password   string
+		remoteAddr string
+		want       bool
+	}{
+		{name: "Correct", username: "user", password: "pass", remoteAddr: "1.1.1.1:1", want: true},
+		{name: "WrongPassword", username: "user", password: "wrong", remoteAddr: "1.1.1.2:1", want: false},
+		{name: "UnknownUser", username: "nobody", password: "pass", remoteAddr: "1.1.1.3:1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if got := authFunc(tt.username, tt.password, r); got != tt.want {
+				t.Errorf("AuthFunc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialStoreRateLimit(t *testing.T) {
+	hash, err := NewPasswordHash("pass")
+	if err != nil {
+		t.Fatalf("NewPasswordHash() error = %v", err)
+	}
+
+	store := New(time.Minute)
+	defer store.Close()
+	store.Set("user", hash)
+
+	authFunc := store.AuthFunc()
+	r := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+	r.RemoteAddr = "1.1.1.1:1"
+
+	if !authFunc("user", "pass", r) {
+		t.Fatal("first attempt should be allowed")
+	}
+	if authFunc("user", "pass", r) {
+		t.Fatal("second attempt within the interval should be throttled")
+	}
+}
+
+func TestRateLimiterExpire(t *testing.T) {
+	rl := newRateLimiter(time.Minute)
+	rl.Allow("1.1.1.1")
+	rl.Expire(0)
+	if _, ok := rl.last["1.1.1.1"]; ok {
+		t.Fatal("expected entry to be expired")
+	}
+}
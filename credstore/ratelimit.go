@@ -0,0 +1,49 @@
+package credstore
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter enforces a minimum interval between successive attempts keyed
+// by an arbitrary string (typically a source IP), acting as a single-token
+// bucket that refills once per interval.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[string]time.Time
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		interval: interval,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether an attempt for key is permitted now, recording the
+// attempt if so.
+func (rl *rateLimiter) Allow(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := rl.last[key]; ok && now.Sub(last) < rl.interval {
+		return false
+	}
+	rl.last[key] = now
+	return true
+}
+
+// Expire removes entries that have not been touched in at least maxAge.
+func (rl *rateLimiter) Expire(maxAge time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	for key, t := range rl.last {
+		if now.Sub(t) >= maxAge {
+			delete(rl.last, key)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package httpware
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDigestAuth(t *testing.T) {
+	const username, realm, password = "alice", "Restricted", "secret"
+	ha1 := hexHash(sha256.New, username+":"+realm+":"+password)
+
+	opts := DigestAuthOptions{
+		Realm: realm,
+		HA1Func: func(u, r string) (string, bool) {
+			if u != username || r != realm {
+				return "", false
+			}
+			return ha1, true
+		},
+	}
+	h := DigestAuth(opts)(handleOK())
+
+	// First request: no credentials, expect a 401 challenge.
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/secret", nil)
+	req.RemoteAddr = "203.0.113.1:54321"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("initial request: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	challenge := rec.Header().Get("WWW-Authenticate")
+	if challenge == "" {
+		t.Fatal("initial request: missing WWW-Authenticate header")
+	}
+	params, ok := parseDigestHeader(challenge)
+	if !ok {
+		t.Fatalf("could not parse challenge %q", challenge)
+	}
+
+	// Second request: respond to the challenge with a correctly computed digest.
+	const (
+		method = http.MethodGet
+		uri    = "/secret"
+		nc     = "00000001"
+		cnonce = "client-nonce"
+		qop    = "auth"
+	)
+	ha2 := hexHash(sha256.New, method+":"+uri)
+	response := hexHash(sha256.New, ha1+":"+params["nonce"]+":"+nc+":"+cnonce+":"+qop+":"+ha2)
+
+	authHeader := fmt.Sprintf(
+		`Digest username=%q, realm=%q, nonce=%q, uri=%q, algorithm=SHA-256, qop=%s, nc=%s, cnonce=%q, response=%q, opaque=%q`,
+		username, realm, params["nonce"], uri, qop, nc, cnonce, response, params["opaque"],
+	)
+
+	req2 := httptest.NewRequest(method, "https://example.com"+uri, nil)
+	req2.RemoteAddr = "203.0.113.1:54321"
+	req2.Header.Set("Authorization", authHeader)
+	rec2 := httptest.NewRecorder()
+	h.ServeHTTP(rec2, req2)
+
+	if rec2.Code != http.StatusOK {
+		t.Fatalf("authenticated request: status = %d, want %d", rec2.Code, http.StatusOK)
+	}
+
+	// Replaying the same request must be rejected.
+	rec3 := httptest.NewRecorder()
+	h.ServeHTTP(rec3, req2)
+	if rec3.Code == http.StatusOK {
+		t.Fatal("replayed request should not be authorized")
+	}
+}
+
+func TestDigestAuthInvalidNonce(t *testing.T) {
+	opts := DigestAuthOptions{
+		HA1Func: func(u, r string) (string, bool) {
+			return hexHash(sha256.New, u+":"+r+":secret"), true
+		},
+	}
+	h := DigestAuth(opts)(handleOK())
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/secret", nil)
+	req.Header.Set("Authorization", `Digest username="alice", realm="Restricted", nonce="bogus", uri="/secret", algorithm=SHA-256, qop=auth, nc=00000001, cnonce="x", response="deadbeef", opaque="y"`)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,44 @@
+package httpware
+
+import "strings"
+
+// parseDigestHeader parses an "Authorization: Digest ..." header into its
+// comma-separated key=value (or key="value") parameters. It reports false
+// if header does not use the Digest scheme.
+func parseDigestHeader(header string) (map[string]string, bool) {
+	scheme, rest, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Digest") {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for _, field := range splitDigestFields(rest) {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			continue
+		}
+		params[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return params, true
+}
+
+// splitDigestFields splits a Digest parameter list on commas, ignoring
+// commas that appear inside quoted values.
+func splitDigestFields(s string) []string {
+	var fields []string
+	var inQuotes bool
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				fields = append(fields, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	fields = append(fields, s[start:])
+	return fields
+}
@@ -0,0 +1,126 @@
+package htpasswd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// verifyPassword reports whether password matches hash, detecting the hash
+// format from its prefix. Supported formats are bcrypt ("$2y$", "$2a$",
+// "$2b$"), SHA-1 ("{SHA}"), APR1 MD5-crypt ("$apr1$"), and, failing all of
+// those, plaintext.
+func verifyPassword(hash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$2y$"), strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		return err == nil, nil
+	case strings.HasPrefix(hash, "{SHA}"):
+		return verifySHA1(hash, password), nil
+	case strings.HasPrefix(hash, "$apr1$"):
+		return verifyAPR1(hash, password)
+	default:
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(password)) == 1, nil
+	}
+}
+
+// verifySHA1 verifies the Apache "{SHA}base64(sha1(password))" format.
+func verifySHA1(hash, password string) bool {
+	sum := sha1.Sum([]byte(password))
+	want := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(want)) == 1
+}
+
+// apr1Alphabet is the alphabet used by the APR1 MD5-crypt algorithm to
+// encode its digest.
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// verifyAPR1 verifies the Apache "$apr1$salt$hash" MD5-crypt format.
+func verifyAPR1(hash, password string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[1] != "apr1" {
+		return false, fmt.Errorf("htpasswd: malformed apr1 hash")
+	}
+	salt := parts[2]
+	want := apr1Crypt(password, salt)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(want)) == 1, nil
+}
+
+// apr1Crypt computes the "$apr1$salt$digest" encoding of password using the
+// APR1 variant of the MD5-crypt algorithm.
+func apr1Crypt(password, salt string) string {
+	magic := "$apr1$"
+
+	ctx := md5.New()
+	ctx.Write([]byte(password))
+	ctx.Write([]byte(magic))
+	ctx.Write([]byte(salt))
+
+	ctx1 := md5.New()
+	ctx1.Write([]byte(password))
+	ctx1.Write([]byte(salt))
+	ctx1.Write([]byte(password))
+	final := ctx1.Sum(nil)
+
+	for i := len(password); i > 0; i -= 16 {
+		n := i
+		if n > 16 {
+			n = 16
+		}
+		ctx.Write(final[:n])
+	}
+	for i := len(password); i != 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write([]byte(password[:1]))
+		}
+	}
+	final = ctx.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		ctx := md5.New()
+		if i&1 != 0 {
+			ctx.Write([]byte(password))
+		} else {
+			ctx.Write(final)
+		}
+		if i%3 != 0 {
+			ctx.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			ctx.Write([]byte(password))
+		}
+		if i&1 != 0 {
+			ctx.Write(final)
+		} else {
+			ctx.Write([]byte(password))
+		}
+		final = ctx.Sum(nil)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(magic)
+	sb.WriteString(salt)
+	sb.WriteByte('$')
+
+	permute := [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}}
+	for _, p := range permute {
+		v := uint32(final[p[0]])<<16 | uint32(final[p[1]])<<8 | uint32(final[p[2]])
+		for j := 0; j < 4; j++ {
+			sb.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	v := uint32(final[11])
+	for j := 0; j < 2; j++ {
+		sb.WriteByte(apr1Alphabet[v&0x3f])
+		v >>= 6
+	}
+	return sb.String()
+}
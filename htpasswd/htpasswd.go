@@ -0,0 +1,146 @@
+// Package htpasswd loads Apache-style htpasswd files and adapts them into
+// httpware.BasicAuthOptions.AuthFunc implementations.
+package htpasswd
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// pollInterval is how often a File checks its source for mtime changes.
+const pollInterval = 5 * time.Second
+
+// File represents a parsed htpasswd file that can be reloaded as its
+// underlying file changes on disk.
+type File struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[string]string
+	modTime time.Time
+
+	closeOnce sync.Once
+	done      chan struct{}
+	sigCh     chan os.Signal
+}
+
+// Open reads and parses the htpasswd file at path, then begins watching it
+// for changes. The file is reloaded automatically when its mtime advances or
+// when the process receives SIGHUP.
+//
+// Lines are of the form "user:hash". Blank lines and lines beginning with
+// '#' are ignored, matching Apache's own htpasswd parser.
+func Open(path string) (*File, error) {
+	f := &File{
+		path:  path,
+		done:  make(chan struct{}),
+		sigCh: make(chan os.Signal, 1),
+	}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+	signal.Notify(f.sigCh, syscall.SIGHUP)
+	go f.watch()
+	return f, nil
+}
+
+// Authenticate reports whether password is the correct password for user,
+// according to the most recently loaded contents of the htpasswd file.
+func (f *File) Authenticate(user, password string) bool {
+	f.mu.RLock()
+	hash, ok := f.entries[user]
+	f.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	ok, err := verifyPassword(hash, password)
+	return err == nil && ok
+}
+
+// Close stops watching the htpasswd file for changes. It is safe to call
+// Close more than once.
+func (f *File) Close() error {
+	f.closeOnce.Do(func() {
+		signal.Stop(f.sigCh)
+		close(f.done)
+	})
+	return nil
+}
+
+// watch reloads the file whenever its mtime changes or SIGHUP is received,
+// until Close is called.
+func (f *File) watch() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-f.sigCh:
+			_ = f.reload()
+		case <-ticker.C:
+			info, err := os.Stat(f.path)
+			if err != nil {
+				continue
+			}
+			f.mu.RLock()
+			changed := info.ModTime().After(f.modTime)
+			f.mu.RUnlock()
+			if changed {
+				_ = f.reload()
+			}
+		}
+	}
+}
+
+// reload reads the htpasswd file from disk and atomically replaces the
+// in-memory entries.
+func (f *File) reload() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		entries[user] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("htpasswd: %w", err)
+	}
+
+	f.mu.Lock()
+	f.entries = entries
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+	return nil
+}
+
+// AuthFunc adapts f into a function usable as httpware.BasicAuthOptions.AuthFunc.
+func AuthFunc(f *File) func(user, password string, r *http.Request) bool {
+	return func(user, password string, _ *http.Request) bool {
+		return f.Authenticate(user, password)
+	}
+}
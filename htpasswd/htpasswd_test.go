@@ -0,0 +1,98 @@
+package htpasswd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{
+			name:     "Plaintext",
+			hash:     "pass",
+			password: "pass",
+			want:     true,
+		},
+		{
+			name:     "PlaintextMismatch",
+			hash:     "pass",
+			password: "wrong",
+			want:     false,
+		},
+		{
+			name:     "SHA1",
+			hash:     "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+			password: "password",
+			want:     true,
+		},
+		{
+			name:     "SHA1Mismatch",
+			hash:     "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+			password: "wrong",
+			want:     false,
+		},
+		{
+			name:     "APR1",
+			hash:     "$apr1$TqI9WV2O$xwOsi8frxVstEPn527N0K.",
+			password: "password",
+			want:     true,
+		},
+		{
+			name:     "APR1Mismatch",
+			hash:     "$apr1$TqI9WV2O$xwOsi8frxVstEPn527N0K.",
+			password: "wrong",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyPassword(tt.hash, tt.password)
+			if err != nil {
+				t.Fatalf("verifyPassword() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("verifyPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileAuthenticate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".htpasswd")
+	contents := "# comment\n\nalice:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\nbob:plain\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		want     bool
+	}{
+		{name: "CorrectSHA1", user: "alice", password: "password", want: true},
+		{name: "WrongSHA1", user: "alice", password: "wrong", want: false},
+		{name: "CorrectPlaintext", user: "bob", password: "plain", want: true},
+		{name: "UnknownUser", user: "eve", password: "anything", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Authenticate(tt.user, tt.password); got != tt.want {
+				t.Errorf("Authenticate(%q, %q) = %v, want %v", tt.user, tt.password, got, tt.want)
+			}
+		})
+	}
+}
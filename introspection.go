@@ -0,0 +1,124 @@
+package httpware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IntrospectionValidator validates bearer tokens by calling an RFC 7662
+// OAuth 2.0 Token Introspection endpoint, caching successful results until
+// the token's reported expiry.
+type IntrospectionValidator struct {
+	endpoint     string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]introspectionCacheEntry
+}
+
+type introspectionCacheEntry struct {
+	claims Claims
+	expiry time.Time
+}
+
+// IntrospectionOption configures an IntrospectionValidator.
+type IntrospectionOption func(*IntrospectionValidator)
+
+// WithIntrospectionHTTPClient sets the http.Client used to call the
+// introspection endpoint.
+func WithIntrospectionHTTPClient(c *http.Client) IntrospectionOption {
+	return func(v *IntrospectionValidator) { v.httpClient = c }
+}
+
+// NewIntrospectionValidator returns an IntrospectionValidator that calls
+// endpoint with clientID and clientSecret as described by RFC 7662. Its
+// Validate method implements TokenValidator.
+func NewIntrospectionValidator(endpoint, clientID, clientSecret string, opts ...IntrospectionOption) *IntrospectionValidator {
+	v := &IntrospectionValidator{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   http.DefaultClient,
+		cache:        make(map[string]introspectionCacheEntry),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// introspectionResponse is the subset of RFC 7662 §2.2 response fields this
+// package understands.
+type introspectionResponse struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub"`
+	Scope     string `json:"scope"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Validate calls the introspection endpoint for token, implementing
+// TokenValidator. Successful results are cached until the token's exp.
+func (v *IntrospectionValidator) Validate(ctx context.Context, token string) (Claims, error) {
+	if claims, ok := v.cached(token); ok {
+		return claims, nil
+	}
+
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("httpware: building introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(v.clientID, v.clientSecret)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("httpware: introspection request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("httpware: introspection request: unexpected status %s", resp.Status)
+	}
+
+	var ir introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ir); err != nil {
+		return nil, fmt.Errorf("httpware: decoding introspection response: %w", err)
+	}
+	if !ir.Active {
+		return nil, fmt.Errorf("httpware: token is not active")
+	}
+
+	claims := Claims{"sub": ir.Subject, "scope": ir.Scope, "exp": ir.ExpiresAt}
+	if ir.ExpiresAt > 0 {
+		v.cacheResult(token, claims, time.Unix(ir.ExpiresAt, 0))
+	}
+	return claims, nil
+}
+
+func (v *IntrospectionValidator) cached(token string) (Claims, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.cache[token]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiry) {
+		delete(v.cache, token)
+		return nil, false
+	}
+	return entry.claims, true
+}
+
+func (v *IntrospectionValidator) cacheResult(token string, claims Claims, expiry time.Time) {
+	v.mu.Lock()
+	v.cache[token] = introspectionCacheEntry{claims: claims, expiry: expiry}
+	v.mu.Unlock()
+}
@@ -0,0 +1,229 @@
+package httpware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultJWKSRefreshInterval is how often a JWKSValidator re-fetches its
+// signing keys in the background.
+const defaultJWKSRefreshInterval = 15 * time.Minute
+
+// JWKSValidator validates JWT bearer tokens, fetching and caching signing
+// keys from a JSON Web Key Set (RFC 7517) endpoint and refreshing them
+// periodically. It implements TokenValidator via its Validate method.
+type JWKSValidator struct {
+	jwksURL         string
+	httpClient      *http.Client
+	refreshInterval time.Duration
+	algorithms      []string
+	hmacSecret      []byte
+
+	mu   sync.RWMutex
+	keys map[string]interface{}
+
+	done chan struct{}
+}
+
+// JWKSOption configures a JWKSValidator.
+type JWKSOption func(*JWKSValidator)
+
+// WithHTTPClient sets the http.Client used to fetch the JWKS document.
+func WithHTTPClient(c *http.Client) JWKSOption {
+	return func(v *JWKSValidator) { v.httpClient = c }
+}
+
+// WithJWKSRefreshInterval sets how often the JWKS document is re-fetched.
+func WithJWKSRefreshInterval(d time.Duration) JWKSOption {
+	return func(v *JWKSValidator) { v.refreshInterval = d }
+}
+
+// WithHMACSecret enables verification of HS256 tokens with the given shared
+// secret, since symmetric keys are not published in a JWKS.
+func WithHMACSecret(secret []byte) JWKSOption {
+	return func(v *JWKSValidator) { v.hmacSecret = secret }
+}
+
+// WithAlgorithms restricts the accepted signing algorithms. Defaults to
+// RS256, ES256, and HS256.
+func WithAlgorithms(algs ...string) JWKSOption {
+	return func(v *JWKSValidator) { v.algorithms = algs }
+}
+
+// NewJWKSValidator fetches the JWKS document at jwksURL and returns a
+// JWKSValidator that keeps its keys fresh in the background until Close is
+// called.
+func NewJWKSValidator(jwksURL string, opts ...JWKSOption) (*JWKSValidator, error) {
+	v := &JWKSValidator{
+		jwksURL:         jwksURL,
+		httpClient:      http.DefaultClient,
+		refreshInterval: defaultJWKSRefreshInterval,
+		algorithms:      []string{"RS256", "ES256", "HS256"},
+		done:            make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if err := v.refresh(); err != nil {
+		return nil, err
+	}
+	go v.refreshLoop()
+	return v, nil
+}
+
+// Close stops the background key refresh. It is safe to call Close more
+// than once.
+func (v *JWKSValidator) Close() error {
+	select {
+	case <-v.done:
+	default:
+		close(v.done)
+	}
+	return nil
+}
+
+func (v *JWKSValidator) refreshLoop() {
+	ticker := time.NewTicker(v.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-ticker.C:
+			_ = v.refresh()
+		}
+	}
+}
+
+// jwkSet is the RFC 7517 JSON Web Key Set document.
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwk is a single RFC 7517/7518 JSON Web Key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (v *JWKSValidator) refresh() error {
+	resp, err := v.httpClient.Get(v.jwksURL)
+	if err != nil {
+		return fmt.Errorf("httpware: fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("httpware: fetching JWKS: unexpected status %s", resp.Status)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("httpware: decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("httpware: decoding RSA modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("httpware: decoding RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("httpware: decoding EC x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("httpware: decoding EC y coordinate: %w", err)
+		}
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("httpware: unsupported EC curve %q", k.Crv)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("httpware: unsupported key type %q", k.Kty)
+	}
+}
+
+// Validate parses and verifies token, implementing TokenValidator.
+func (v *JWKSValidator) Validate(_ context.Context, token string) (Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, v.keyFunc, jwt.WithValidMethods(v.algorithms))
+	if err != nil {
+		return nil, fmt.Errorf("httpware: invalid token: %w", err)
+	}
+	return Claims(claims), nil
+}
+
+func (v *JWKSValidator) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if v.hmacSecret == nil {
+			return nil, fmt.Errorf("httpware: HS256 token but no HMAC secret configured")
+		}
+		return v.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("httpware: unknown key id %q", kid)
+	}
+	return key, nil
+}